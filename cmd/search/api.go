@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// searchRecord is one matched line, structured for programmatic consumers
+// that would otherwise have to scrape the bootstrap markup handleIndex
+// renders. line_number and timestamp are intentionally not included here:
+// executeGrep's callback only hands us the rendered context block, not an
+// artifact byte/line offset or the job's completion time, so there is
+// nothing honest to put in those fields yet.
+type searchRecord struct {
+	Job           string `json:"job,omitempty"`
+	Build         string `json:"build,omitempty"`
+	PR            string `json:"pr,omitempty"`
+	File          string `json:"file"`
+	Match         string `json:"match"`
+	ContextBefore string `json:"context_before,omitempty"`
+	ContextAfter  string `json:"context_after,omitempty"`
+	URL           string `json:"url,omitempty"`
+}
+
+// parseAPIIndex parses the subset of query parameters the JSON/NDJSON
+// endpoints accept, reusing handleIndex's validation rules except that
+// ?search is required and ?context must be 0 to 15 (handleIndex also accepts
+// -1, its links-only mode).
+func (o *options) parseAPIIndex(req *http.Request) (*Index, error) {
+	index := &Index{
+		Context: 2,
+		MaxAge:  7 * 24 * time.Hour,
+	}
+
+	index.Search = req.FormValue("search")
+	if len(index.Search) == 0 {
+		return nil, fmt.Errorf("?search is required")
+	}
+
+	if value := req.FormValue("context"); len(value) > 0 {
+		num, err := strconv.Atoi(value)
+		if err != nil || num < 0 || num > 15 {
+			return nil, fmt.Errorf("?context must be a number between 0 and 15")
+		}
+		index.Context = num
+	}
+
+	switch req.FormValue("type") {
+	case "junit":
+		index.SearchType = "junit"
+	case "build-log":
+		index.SearchType = "build-log"
+	case "finding":
+		index.SearchType = "finding"
+	case "all", "":
+		index.SearchType = "all"
+	default:
+		return nil, fmt.Errorf("?type must be 'junit', 'build-log', 'finding', or 'all'")
+	}
+
+	if value := req.FormValue("maxAge"); len(value) > 0 {
+		maxAge, err := time.ParseDuration(value)
+		if err != nil || maxAge < 0 {
+			return nil, fmt.Errorf("?maxAge must be a non-negative duration")
+		}
+		index.MaxAge = maxAge
+	}
+	if o.MaxAge > 0 && o.MaxAge < index.MaxAge {
+		index.MaxAge = o.MaxAge
+	}
+
+	return index, nil
+}
+
+// recordFromMatch builds a searchRecord out of one executeGrep callback
+// invocation, splitting the context block around the matched line the same
+// way index.Context lines of context are rendered either side of it in
+// renderWithContext.
+func recordFromMatch(name string, matches []bytes.Buffer, index *Index) searchRecord {
+	record := searchRecord{File: name}
+
+	parts := strings.Split(name, string(filepath.Separator))
+	last := len(parts) - 1
+	if last > 2 && (parts[last] == "junit.failures" || parts[last] == "build-log.txt") {
+		record.Build = parts[last-1]
+		record.Job = parts[last-2]
+		record.URL = "https://openshift-gce-devel.appspot.com/build/" + strings.Join(parts[:last], "/")
+		if last > 3 && parts[2] == "pull" {
+			record.PR = parts[3]
+		}
+	}
+
+	matchLine := index.Context
+	if matchLine >= len(matches) {
+		matchLine = len(matches) - 1
+	}
+	if matchLine < 0 {
+		matchLine = 0
+	}
+
+	var before, after []string
+	for i, m := range matches {
+		line := string(bytes.TrimRight(m.Bytes(), " "))
+		switch {
+		case i < matchLine:
+			before = append(before, line)
+		case i == matchLine:
+			record.Match = line
+		default:
+			after = append(after, line)
+		}
+	}
+	record.ContextBefore = strings.Join(before, "\n")
+	record.ContextAfter = strings.Join(after, "\n")
+	return record
+}
+
+// projectFields trims record down to only the requested fields, returning a
+// map[string]interface{} suitable for json.Marshal, or record itself
+// unprojected if fields is empty.
+func projectFields(record searchRecord, fields []string) interface{} {
+	if len(fields) == 0 {
+		return record
+	}
+	data, _ := json.Marshal(record)
+	var all map[string]interface{}
+	json.Unmarshal(data, &all)
+	projected := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		if v, ok := all[field]; ok {
+			projected[field] = v
+		}
+	}
+	return projected
+}
+
+// handleSearchJSON reuses the same Index parsing and executeGrep callback
+// as handleIndex, but buffers every match into a single JSON array response
+// instead of rendering HTML.
+func (o *options) handleSearchJSON(w http.ResponseWriter, req *http.Request) {
+	o.handleSearchRecords(w, req, false)
+}
+
+// handleSearchNDJSON streams one JSON record per match as it is found, so
+// long-running searches are consumable incrementally by scripts and bots.
+func (o *options) handleSearchNDJSON(w http.ResponseWriter, req *http.Request) {
+	o.handleSearchRecords(w, req, true)
+}
+
+func (o *options) handleSearchRecords(w http.ResponseWriter, req *http.Request, stream bool) {
+	if err := req.ParseForm(); err != nil {
+		http.Error(w, fmt.Sprintf("Bad input: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	index, err := o.parseAPIIndex(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var fields []string
+	if value := req.FormValue("fields"); len(value) > 0 {
+		fields = strings.Split(value, ",")
+	}
+
+	limit := 0
+	if value := req.FormValue("limit"); len(value) > 0 {
+		num, err := strconv.Atoi(value)
+		if err != nil || num < 1 {
+			http.Error(w, "?limit must be a positive number", http.StatusBadRequest)
+			return
+		}
+		limit = num
+	}
+
+	after := 0
+	if value := req.FormValue("after"); len(value) > 0 {
+		num, err := strconv.Atoi(value)
+		if err != nil || num < 0 {
+			http.Error(w, "?after must be a non-negative cursor", http.StatusBadRequest)
+			return
+		}
+		after = num
+	}
+
+	flusher, canFlush := w.(http.Flusher)
+
+	if stream {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+	}
+
+	enc := json.NewEncoder(w)
+	seen := 0
+	emitted := 0
+	if !stream {
+		fmt.Fprint(w, "[")
+	}
+	err = executeGrep(req.Context(), o.generator, index, 30, func(name string, matches []bytes.Buffer, moreLines int) {
+		seen++
+		if seen <= after {
+			return
+		}
+		if limit > 0 && emitted >= limit {
+			return
+		}
+		record := recordFromMatch(name, matches, index)
+		out := projectFields(record, fields)
+
+		if stream {
+			enc.Encode(out)
+			if canFlush {
+				flusher.Flush()
+			}
+		} else {
+			if emitted > 0 {
+				fmt.Fprint(w, ",")
+			}
+			data, _ := json.Marshal(out)
+			w.Write(data)
+		}
+		emitted++
+	})
+	if !stream {
+		fmt.Fprint(w, "]")
+	}
+	if err != nil {
+		glog.Errorf("Command exited with error: %v", err)
+	}
+}
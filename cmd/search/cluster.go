@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"net/http"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// maxClusterRepresentatives bounds the number of active cluster
+// representatives so clustering latency stays comparable to the
+// unclustered search path even against a long tail of distinct failures.
+const maxClusterRepresentatives = 500
+
+// clusterMatchFraction caps how different a line may be from a
+// representative (as a fraction of the representative's length) and still
+// be considered the same failure signature.
+const clusterMatchFraction = 0.4
+
+// normalizers strip volatile tokens (timestamps, UUIDs, hex addresses, pod
+// name suffixes, line numbers) before lines are compared, so that two
+// otherwise-identical failures don't land in separate clusters just because
+// they happened at different times or on different pods.
+var normalizers = []*regexp.Regexp{
+	regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`),
+	regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`),
+	regexp.MustCompile(`0x[0-9a-fA-F]+`),
+	regexp.MustCompile(`-[0-9a-z]{5}\b`),
+	regexp.MustCompile(`:\d+\b`),
+	regexp.MustCompile(`\d+`),
+}
+
+// normalizeLine substitutes every volatile token pattern with a single
+// placeholder so edit distance reflects the stable shape of the line.
+func normalizeLine(line []byte) string {
+	s := string(line)
+	for _, re := range normalizers {
+		s = re.ReplaceAllString(s, "#")
+	}
+	return s
+}
+
+// lineCluster groups matched lines that are within clusterMatchFraction of
+// each other after normalization.
+type lineCluster struct {
+	representative string
+	original       string
+	members        []string
+	jobs           map[string]struct{}
+	totalDistance  int
+}
+
+// clusterSet is a bounded, LRU-evicted set of clusters built incrementally
+// as matches stream in.
+type clusterSet struct {
+	clusters []*lineCluster
+	order    []*lineCluster // most-recently-touched last, for LRU eviction
+}
+
+func newClusterSet() *clusterSet {
+	return &clusterSet{}
+}
+
+// Add normalizes line, finds the nearest existing cluster within threshold,
+// and either assigns it there or creates a new cluster, evicting the least
+// recently touched cluster if the set is already at capacity.
+func (cs *clusterSet) Add(job string, original string) {
+	normalized := normalizeLine([]byte(original))
+
+	best := -1
+	bestDistance := -1
+	for i, c := range cs.clusters {
+		threshold := int(float64(len(c.representative)) * clusterMatchFraction)
+		if threshold < 1 {
+			threshold = 1
+		}
+		d := boundedLevenshtein(normalized, c.representative, threshold)
+		if d < 0 {
+			continue
+		}
+		if best == -1 || d < bestDistance {
+			best, bestDistance = i, d
+		}
+	}
+
+	var c *lineCluster
+	if best >= 0 {
+		c = cs.clusters[best]
+	} else {
+		if len(cs.clusters) >= maxClusterRepresentatives {
+			cs.evictOldest()
+		}
+		c = &lineCluster{representative: normalized, original: original, jobs: map[string]struct{}{}}
+		cs.clusters = append(cs.clusters, c)
+		bestDistance = 0
+	}
+
+	c.members = append(c.members, original)
+	c.jobs[job] = struct{}{}
+	c.totalDistance += bestDistance
+	cs.touch(c)
+}
+
+func (cs *clusterSet) touch(c *lineCluster) {
+	for i, o := range cs.order {
+		if o == c {
+			cs.order = append(cs.order[:i], cs.order[i+1:]...)
+			break
+		}
+	}
+	cs.order = append(cs.order, c)
+}
+
+func (cs *clusterSet) evictOldest() {
+	if len(cs.order) == 0 {
+		return
+	}
+	oldest := cs.order[0]
+	cs.order = cs.order[1:]
+	for i, c := range cs.clusters {
+		if c == oldest {
+			cs.clusters = append(cs.clusters[:i], cs.clusters[i+1:]...)
+			break
+		}
+	}
+}
+
+// boundedLevenshtein computes the edit distance between a and b using the
+// standard two-rolling-rows DP, but bails out early (returning -1) once the
+// distance is certain to exceed threshold, keeping each comparison O(band)
+// rather than O(len(a)*len(b)) for dissimilar lines.
+func boundedLevenshtein(a, b string, threshold int) int {
+	if abs(len(a)-len(b)) > threshold {
+		return -1
+	}
+	// keep b the shorter string so the rolling rows are sized off it.
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+
+	width := len(b) + 1
+	prev := make([]int, width)
+	curr := make([]int, width)
+	for j := 0; j < width; j++ {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if curr[j] < rowMin {
+				rowMin = curr[j]
+			}
+		}
+		if rowMin > threshold {
+			return -1
+		}
+		prev, curr = curr, prev
+	}
+
+	d := prev[len(b)]
+	if d > threshold {
+		return -1
+	}
+	return d
+}
+
+func min3(a, b, c int) int {
+	m := a
+	if b < m {
+		m = b
+	}
+	if c < m {
+		m = c
+	}
+	return m
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// renderClustered streams matches through executeGrep exactly like
+// renderWithContext, but groups matched lines into failure-signature
+// clusters instead of per-file sections. minCluster hides clusters with
+// fewer than minCluster members (singletons by default).
+func renderClustered(ctx context.Context, w http.ResponseWriter, index *Index, generator CommandGenerator, start time.Time, minCluster int) (int, error) {
+	cs := newClusterSet()
+
+	err := executeGrep(ctx, generator, index, 30, func(name string, matches []bytes.Buffer, moreLines int) {
+		// matches is the full context window (index.Context lines before
+		// and after the match); only the matched line itself - at index
+		// index.Context, the same derivation recordFromMatch uses - should
+		// feed the clusterer, or context boilerplate dwarfs real failures.
+		matchLine := index.Context
+		if matchLine >= len(matches) {
+			matchLine = len(matches) - 1
+		}
+		if matchLine < 0 {
+			return
+		}
+		line := bytes.TrimRight(matches[matchLine].Bytes(), " ")
+		if len(line) == 0 {
+			return
+		}
+		cs.Add(name, string(line))
+	})
+
+	sort.Slice(cs.clusters, func(i, j int) bool { return len(cs.clusters[i].members) > len(cs.clusters[j].members) })
+
+	bw := bufio.NewWriterSize(w, 256*1024)
+	count := 0
+	for _, c := range cs.clusters {
+		if len(c.members) < minCluster {
+			continue
+		}
+		count++
+		meanDistance := float64(c.totalDistance) / float64(len(c.members))
+		fmt.Fprintf(bw, `<div class="mb-4">`)
+		fmt.Fprintf(bw, `<h5 class="mb-2">%s <span class="text-muted small">(%d occurrences across %d jobs, mean distance %.1f)</span></h5>`,
+			template.HTMLEscapeString(c.original), len(c.members), len(c.jobs), meanDistance)
+		fmt.Fprintf(bw, `<details><summary>jobs</summary><ul class="small">`)
+		for job := range c.jobs {
+			fmt.Fprintf(bw, `<li>%s</li>`, template.HTMLEscapeString(job))
+		}
+		fmt.Fprintf(bw, `</ul></details></div>`)
+	}
+	if err := bw.Flush(); err != nil {
+		return count, err
+	}
+	return count, err
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/golang/glog"
+
+	"github.com/Global19-atlassian-net/ci-search/pkg/scraper"
+)
+
+// handleFindings aggregates the sidecar findings written by the scraper
+// under o.JobsPath over the last o.MaxAge (or ?maxAge=) and reports counts
+// per rule, so users can discover recurring failure classes without
+// hand-writing regexes on every search.
+func (o *options) handleFindings(w http.ResponseWriter, req *http.Request) {
+	if err := req.ParseForm(); err != nil {
+		http.Error(w, fmt.Sprintf("Bad input: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	maxAge := o.MaxAge
+	if value := req.FormValue("maxAge"); len(value) > 0 {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			http.Error(w, "?maxAge must be a non-negative duration", http.StatusInternalServerError)
+			return
+		}
+		maxAge = parsed
+	}
+
+	counts, err := aggregateFindings(o.JobsPath, maxAge)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Unable to aggregate findings: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	fmt.Fprintf(w, htmlPageStart, "Findings - Search OpenShift CI")
+	fmt.Fprintf(w, `<table class="table table-sm"><thead><tr><th>Rule</th><th>Count</th></tr></thead><tbody>`)
+	for _, c := range counts {
+		fmt.Fprintf(w, `<tr><td>%s</td><td>%d</td></tr>`, template.HTMLEscapeString(c.Rule), c.Count)
+	}
+	fmt.Fprintf(w, `</tbody></table>`)
+	fmt.Fprintf(w, htmlPageEnd)
+}
+
+type ruleCount struct {
+	Rule  string
+	Count int
+}
+
+// aggregateFindings walks dir for *.findings sidecar files modified within
+// maxAge and tallies the number of findings per rule name.
+func aggregateFindings(dir string, maxAge time.Duration) ([]ruleCount, error) {
+	counts := make(map[string]int)
+	cutoff := time.Now().Add(-maxAge)
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Ext(path) != ".findings" {
+			return nil
+		}
+		if maxAge > 0 && info.ModTime().Before(cutoff) {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			glog.Warningf("Unable to open findings sidecar %s: %v", path, err)
+			return nil
+		}
+		defer f.Close()
+
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var finding scraper.Finding
+			if err := json.Unmarshal(scanner.Bytes(), &finding); err != nil {
+				continue
+			}
+			counts[finding.Rule]++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ruleCount, 0, len(counts))
+	for rule, count := range counts {
+		result = append(result, ruleCount{Rule: rule, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Count > result[j].Count })
+	return result, nil
+}
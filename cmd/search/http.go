@@ -61,6 +61,10 @@ func (o *options) handleIndex(w http.ResponseWriter, req *http.Request) {
 		}
 		index.Context = num
 	}
+	if req.FormValue("cluster") == "1" && index.Context < 0 {
+		http.Error(w, "?cluster=1 cannot be combined with ?context=-1", http.StatusInternalServerError)
+		return
+	}
 	contextOptions := []string{
 		fmt.Sprintf(`<option value="-1" %s>Links</option>`, intSelected(1, index.Context)),
 		fmt.Sprintf(`<option value="0" %s>No context</option>`, intSelected(0, index.Context)),
@@ -84,14 +88,16 @@ func (o *options) handleIndex(w http.ResponseWriter, req *http.Request) {
 		index.SearchType = "junit"
 	case "build-log":
 		index.SearchType = "build-log"
+	case "finding":
+		index.SearchType = "finding"
 	case "all", "":
 		index.SearchType = "all"
 	default:
-		http.Error(w, "?search must be 'junit', 'build-log', or 'all'", http.StatusInternalServerError)
+		http.Error(w, "?type must be 'junit', 'build-log', 'finding', or 'all'", http.StatusInternalServerError)
 		return
 	}
 	var searchTypeOptions []string
-	for _, searchType := range []string{"junit", "build-log", "all"} {
+	for _, searchType := range []string{"junit", "build-log", "finding", "all"} {
 		var selected string
 		if searchType == index.SearchType {
 			selected = "selected"
@@ -146,9 +152,21 @@ func (o *options) handleIndex(w http.ResponseWriter, req *http.Request) {
 
 	var count int
 	var err error
-	if index.Context >= 0 {
+	switch {
+	case req.FormValue("cluster") == "1":
+		minCluster := 2
+		if value := req.FormValue("minCluster"); len(value) > 0 {
+			num, err := strconv.Atoi(value)
+			if err != nil || num < 1 {
+				http.Error(w, "?minCluster must be a positive number", http.StatusInternalServerError)
+				return
+			}
+			minCluster = num
+		}
+		count, err = renderClustered(req.Context(), w, index, o.generator, start, minCluster)
+	case index.Context >= 0:
 		count, err = renderWithContext(req.Context(), w, index, o.generator, start)
-	} else {
+	default:
 		count, err = renderSummary(req.Context(), w, index, o.generator, start)
 	}
 
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/Global19-atlassian-net/ci-search/pkg/nettransport"
+)
+
+// listenAndServe binds srv with o.Listen if one is configured - e.g. one
+// returning a nettransport.LoopbackListener, so the process never has to open a
+// host-kernel socket - falling back to the host network stack otherwise.
+// prow.Client
+// should be constructed with http.Client{Transport:
+// nettransport.RoundTripper(o.DialContext)} so requests to Deck go through
+// the same hook.
+func (o *options) listenAndServe(srv *http.Server) error {
+	listen := o.Listen
+	if listen == nil {
+		listen = net.Listen
+	}
+	l, err := listen("tcp", srv.Addr)
+	if err != nil {
+		return err
+	}
+	return srv.Serve(l)
+}
+
+// compile-time assertions that the stdlib network stack already satisfies
+// the hooks nettransport expects, so swapping in a userspace listener/dialer
+// requires no change anywhere else.
+var (
+	_ nettransport.ListenFunc = net.Listen
+)
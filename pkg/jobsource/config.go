@@ -0,0 +1,105 @@
+package jobsource
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"time"
+
+	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Duration wraps time.Duration so source configs can use the familiar "30s"
+// / "5m" string form in both YAML and JSON.
+type Duration time.Duration
+
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %v", s, err)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %v", s, err)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// LoadConfig reads a YAML document containing a list of source configs,
+// following the same "list of named targets" shape as scrape-target config.
+func LoadConfig(path string) ([]Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read job source config %s: %v", path, err)
+	}
+	var cfg struct {
+		Sources []Config `yaml:"sources"`
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("unable to parse job source config %s: %v", path, err)
+	}
+	for i := range cfg.Sources {
+		if len(cfg.Sources[i].Name) == 0 {
+			return nil, fmt.Errorf("source %d in %s is missing a name", i, path)
+		}
+		for j := range cfg.Sources[i].Filters {
+			f := &cfg.Sources[i].Filters[j]
+			re, err := regexp.Compile(f.Regex)
+			if err != nil {
+				return nil, fmt.Errorf("source %q has an invalid filter regex %q: %v", cfg.Sources[i].Name, f.Regex, err)
+			}
+			f.re = re
+		}
+	}
+	return cfg.Sources, nil
+}
+
+// Keep reports whether set passes every configured FilterRule. Filters
+// compose like relabel_configs: a job is dropped as soon as any rule that
+// matches has Drop set, or as soon as a non-drop rule fails to match. Each
+// rule's regex is compiled once by LoadConfig and reused here - this runs
+// per-job, per-poll, so recompiling it on every call would be wasteful
+// across even a modest number of jobs.
+func Keep(filters []FilterRule, set labels.Set) (bool, error) {
+	for _, f := range filters {
+		re := f.re
+		if re == nil {
+			compiled, err := regexp.Compile(f.Regex)
+			if err != nil {
+				return false, err
+			}
+			re = compiled
+		}
+		matched := re.MatchString(set.Get(f.Label))
+		if f.Drop {
+			if matched {
+				return false, nil
+			}
+			continue
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+	return true, nil
+}
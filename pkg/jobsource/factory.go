@@ -0,0 +1,22 @@
+package jobsource
+
+import "fmt"
+
+// New constructs the Source described by cfg. TypeDeck is not handled here:
+// a Deck source needs a prow.Client, so callers build it with
+// prow.NewDeckSource and pass the result alongside whatever this function
+// returns for the remaining entries in a config file.
+func New(cfg Config) (Source, error) {
+	switch cfg.Type {
+	case TypeGCS:
+		return NewGCSSource(cfg)
+	case TypeFilesystem:
+		return NewFilesystemSource(cfg)
+	case TypeManifest:
+		return NewManifestSource(cfg)
+	case TypeDeck:
+		return nil, fmt.Errorf("source %q: deck sources must be built with prow.NewDeckSource", cfg.Name)
+	default:
+		return nil, fmt.Errorf("source %q has unknown type %q (must be one of %q, %q, %q, %q)", cfg.Name, cfg.Type, TypeDeck, TypeGCS, TypeFilesystem, TypeManifest)
+	}
+}
@@ -0,0 +1,138 @@
+package jobsource
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/Global19-atlassian-net/ci-search/pkg/scraper"
+)
+
+// FilesystemSource lists jobs out of a local directory laid out the same
+// way prow's GCS buckets are (<root>/<job>/<build>/...), for local
+// development and downstream forks that mirror artifacts to disk instead of
+// GCS.
+type FilesystemSource struct {
+	name     string
+	root     string
+	interval time.Duration
+	filters  []FilterRule
+	scraper  *scraper.Scraper
+}
+
+// NewFilesystemSource builds a Source that polls cfg.URL, a local directory
+// path, for job/build subdirectories. If cfg.Rules is set, each build
+// directory's build-log.txt and junit.failures are scraped for findings on
+// each poll, producing the ".findings" sidecars the /findings endpoint
+// aggregates; other files in a build directory are not scraped.
+func NewFilesystemSource(cfg Config) (*FilesystemSource, error) {
+	if len(cfg.URL) == 0 {
+		return nil, fmt.Errorf("source %q is missing a root path", cfg.Name)
+	}
+	interval := cfg.Interval.Duration()
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	s := &FilesystemSource{name: cfg.Name, root: cfg.URL, interval: interval, filters: cfg.Filters}
+	if len(cfg.Rules) > 0 {
+		sc, err := scraper.NewScraper(cfg.Rules)
+		if err != nil {
+			return nil, fmt.Errorf("source %q has an invalid rules directory: %v", cfg.Name, err)
+		}
+		s.scraper = sc
+	}
+	return s, nil
+}
+
+func (s *FilesystemSource) Name() string { return s.name }
+
+func (s *FilesystemSource) List(options metav1.ListOptions) (runtime.Object, error) {
+	items, err := s.list()
+	if err != nil {
+		return nil, err
+	}
+	return &JobList{Items: items}, nil
+}
+
+func (s *FilesystemSource) Watch(options metav1.ListOptions) (watch.Interface, error) {
+	return NewPollWatcher(s.interval, s.list), nil
+}
+
+func (s *FilesystemSource) list() ([]Job, error) {
+	jobDirs, err := ioutil.ReadDir(s.root)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list %s: %v", s.root, err)
+	}
+
+	var result []Job
+	for _, jobDir := range jobDirs {
+		if !jobDir.IsDir() {
+			continue
+		}
+		buildDirs, err := ioutil.ReadDir(filepath.Join(s.root, jobDir.Name()))
+		if err != nil {
+			continue
+		}
+		for _, buildDir := range buildDirs {
+			if !buildDir.IsDir() {
+				continue
+			}
+			buildPath := filepath.Join(s.root, jobDir.Name(), buildDir.Name())
+			state := "success"
+			if _, err := os.Stat(filepath.Join(buildPath, "build-log.txt")); err == nil {
+				state = "failure"
+			}
+			if s.scraper != nil {
+				s.scrapeBuild(buildPath)
+			}
+			// prefix with the source name so two sources that happen to
+			// produce the same job/build never collide on the same cache
+			// key.
+			job := Job{
+				ObjectMeta:     metav1.ObjectMeta{Name: s.name + "/" + jobDir.Name() + "-" + buildDir.Name()},
+				Source:         s.name,
+				State:          state,
+				BuildID:        buildDir.Name(),
+				URL:            filepath.Join(s.root, jobDir.Name(), buildDir.Name()),
+				CompletionTime: buildDir.ModTime(),
+			}
+			keep, err := Keep(s.filters, job.Labels())
+			if err != nil {
+				return nil, err
+			}
+			if keep {
+				result = append(result, job)
+			}
+		}
+	}
+	return result, nil
+}
+
+// scrapeBuild applies s.scraper to the build-log.txt and junit.failures
+// artifacts in buildPath, writing a sidecar for each one that produces
+// findings; other files are not scraped, even if a rule's target matches
+// them. Every artifact is re-scraped on every poll, whether or not a sidecar
+// is already present; since WriteSidecar is a no-op for zero findings and scraping is cheap
+// relative to the surrounding directory walk, this keeps the call site
+// simple rather than tracking which artifacts have already been scraped.
+func (s *FilesystemSource) scrapeBuild(buildPath string) {
+	for _, artifact := range []string{"build-log.txt", "junit.failures"} {
+		artifactPath := filepath.Join(buildPath, artifact)
+		f, err := os.Open(artifactPath)
+		if err != nil {
+			continue
+		}
+		findings, err := s.scraper.Scan(artifact, f)
+		f.Close()
+		if err != nil {
+			continue
+		}
+		scraper.WriteSidecar(artifactPath, findings)
+	}
+}
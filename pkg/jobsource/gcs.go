@@ -0,0 +1,129 @@
+package jobsource
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// GCSSource lists jobs directly out of a raw GCS bucket laid out the way
+// prow writes artifacts (gs://bucket/logs/<job>/<build>/...), for operators
+// running CI without a Deck deployment in front of it.
+type GCSSource struct {
+	name     string
+	bucket   string
+	prefix   string
+	interval time.Duration
+	filters  []FilterRule
+	client   *http.Client
+}
+
+// NewGCSSource builds a Source that polls the GCS JSON API for objects
+// under cfg.URL, which must be a gs://bucket/prefix url.
+func NewGCSSource(cfg Config) (*GCSSource, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("source %q has an invalid url: %v", cfg.Name, err)
+	}
+	if u.Scheme != "gs" {
+		return nil, fmt.Errorf("source %q must use a gs:// url, got %q", cfg.Name, cfg.URL)
+	}
+	interval := cfg.Interval.Duration()
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	return &GCSSource{
+		name:     cfg.Name,
+		bucket:   u.Host,
+		prefix:   strings.TrimPrefix(u.Path, "/"),
+		interval: interval,
+		filters:  cfg.Filters,
+		client:   http.DefaultClient,
+	}, nil
+}
+
+func (s *GCSSource) Name() string { return s.name }
+
+func (s *GCSSource) List(options metav1.ListOptions) (runtime.Object, error) {
+	items, err := s.list()
+	if err != nil {
+		return nil, err
+	}
+	return &JobList{Items: items}, nil
+}
+
+func (s *GCSSource) Watch(options metav1.ListOptions) (watch.Interface, error) {
+	return NewPollWatcher(s.interval, s.list), nil
+}
+
+// gcsListResponse is the subset of the GCS JSON API "objects.list" response
+// this source needs.
+type gcsListResponse struct {
+	Items []struct {
+		Name    string    `json:"name"`
+		Updated time.Time `json:"updated"`
+	} `json:"items"`
+}
+
+func (s *GCSSource) list() ([]Job, error) {
+	u := fmt.Sprintf("https://storage.googleapis.com/storage/v1/b/%s/o?prefix=%s", s.bucket, url.QueryEscape(s.prefix))
+	resp, err := s.client.Get(u)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list gs://%s/%s: %v", s.bucket, s.prefix, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unable to list gs://%s/%s: %s", s.bucket, s.prefix, resp.Status)
+	}
+
+	var list gcsListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("unable to decode gs://%s/%s listing: %v", s.bucket, s.prefix, err)
+	}
+
+	jobs := map[string]Job{}
+	for _, obj := range list.Items {
+		// layout is <prefix>/<job>/<build>/...
+		rel := strings.TrimPrefix(strings.TrimPrefix(obj.Name, s.prefix), "/")
+		parts := strings.SplitN(rel, "/", 3)
+		if len(parts) < 2 {
+			continue
+		}
+		job, build := parts[0], parts[1]
+		// prefix with the source name so two sources that happen to
+		// produce the same job/build (e.g. Deck and a GCS mirror of the
+		// same upstream) never collide on the same cache key.
+		name := s.name + "/" + job + "-" + build
+		if existing, ok := jobs[name]; ok && !obj.Updated.After(existing.CompletionTime) {
+			continue
+		}
+		jobs[name] = Job{
+			ObjectMeta:     metav1.ObjectMeta{Name: name},
+			Source:         s.name,
+			State:          "unknown",
+			BuildID:        build,
+			URL:            "gs://" + path.Join(s.bucket, s.prefix, job, build),
+			CompletionTime: obj.Updated,
+		}
+	}
+
+	var result []Job
+	for _, job := range jobs {
+		keep, err := Keep(s.filters, job.Labels())
+		if err != nil {
+			return nil, err
+		}
+		if keep {
+			result = append(result, job)
+		}
+	}
+	return result, nil
+}
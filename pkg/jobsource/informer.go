@@ -0,0 +1,146 @@
+package jobsource
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+// NewLister lists jobs out of a cache fed by one or more Sources.
+func NewLister(indexer cache.Indexer) *Lister {
+	return &Lister{indexer: indexer}
+}
+
+// Lister reads jobs fanned in from every configured Source out of the
+// shared indexer. Selectors match the labels Job.Labels synthesizes
+// (source=..., type=..., org=..., state=...), so callers can scope a query
+// to a single source the same way they'd scope it to a single org.
+type Lister struct {
+	indexer cache.Indexer
+}
+
+func (s *Lister) List(selector labels.Selector) (ret []*Job, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*Job))
+	})
+	return ret, err
+}
+
+func (s *Lister) Get(name string) (*Job, error) {
+	obj, exists, err := s.indexer.GetByKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("job %q not found", name)
+	}
+	return obj.(*Job), nil
+}
+
+// NewInformer fans the given Sources into a single cache.SharedIndexInformer,
+// so the indexer only has to run one reflector regardless of how many
+// backends are configured. Each Source's jobs are already name-qualified by
+// the source (see Job.Source and the per-Source list implementations), so
+// two sources never collide on the same cache key.
+func NewInformer(sources []Source, resyncInterval time.Duration) cache.SharedIndexInformer {
+	lw := &multiSourceListWatch{sources: sources}
+	return cache.NewSharedIndexInformer(&cache.ListWatch{ListFunc: lw.List, WatchFunc: lw.Watch}, &Job{}, resyncInterval, nil)
+}
+
+type multiSourceListWatch struct {
+	sources []Source
+}
+
+func (m *multiSourceListWatch) List(options metav1.ListOptions) (runtime.Object, error) {
+	var items []Job
+	for _, source := range m.sources {
+		obj, err := source.List(options)
+		if err != nil {
+			return nil, fmt.Errorf("source %q: %v", source.Name(), err)
+		}
+		list, ok := obj.(*JobList)
+		if !ok {
+			return nil, fmt.Errorf("source %q: unexpected list type %T", source.Name(), obj)
+		}
+		items = append(items, list.Items...)
+	}
+	return &JobList{Items: items}, nil
+}
+
+func (m *multiSourceListWatch) Watch(options metav1.ListOptions) (watch.Interface, error) {
+	fanIn := newFanInWatch()
+	for _, source := range m.sources {
+		w, err := source.Watch(options)
+		if err != nil {
+			fanIn.Stop()
+			return nil, fmt.Errorf("source %q: %v", source.Name(), err)
+		}
+		fanIn.add(w)
+	}
+	return fanIn, nil
+}
+
+// fanInWatch merges the watch.Event channels of several watch.Interfaces
+// into one, so a single cache.SharedIndexInformer can consume every
+// configured Source as though it were one reflector. Per watch.Interface's
+// contract, Stop closes the channel ResultChan returns - it does so once
+// every per-source forwarding goroutine started by add has exited, so a
+// caller ranging over ResultChan is guaranteed to see the channel close
+// rather than block forever.
+type fanInWatch struct {
+	ch      chan watch.Event
+	done    chan struct{}
+	sources []watch.Interface
+	wg      sync.WaitGroup
+	once    sync.Once
+}
+
+func newFanInWatch() *fanInWatch {
+	return &fanInWatch{ch: make(chan watch.Event, 100), done: make(chan struct{})}
+}
+
+func (f *fanInWatch) add(w watch.Interface) {
+	f.sources = append(f.sources, w)
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		for {
+			select {
+			case event, ok := <-w.ResultChan():
+				if !ok {
+					return
+				}
+				select {
+				case f.ch <- event:
+				case <-f.done:
+					return
+				}
+			case <-f.done:
+				return
+			}
+		}
+	}()
+}
+
+func (f *fanInWatch) Stop() {
+	f.once.Do(func() {
+		close(f.done)
+		for _, w := range f.sources {
+			w.Stop()
+		}
+		go func() {
+			f.wg.Wait()
+			close(f.ch)
+		}()
+	})
+}
+
+func (f *fanInWatch) ResultChan() <-chan watch.Event {
+	return f.ch
+}
@@ -0,0 +1,61 @@
+package jobsource
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Job is the common shape every Source normalizes its backend-specific job
+// records into before they are fanned into the shared indexer.
+type Job struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Source is the name of the Source that produced this job, used to
+	// qualify its cache key so two sources never collide on the same name.
+	Source string `json:"source"`
+	// Type, Org, State mirror the labels Deck jobs already carry (e.g.
+	// "presubmit", "openshift", "failure") so filters and selectors behave
+	// the same regardless of backend.
+	Type           string    `json:"type,omitempty"`
+	Org            string    `json:"org,omitempty"`
+	State          string    `json:"state"`
+	URL            string    `json:"url,omitempty"`
+	BuildID        string    `json:"buildID,omitempty"`
+	CompletionTime time.Time `json:"completionTime,omitempty"`
+}
+
+// DeepCopyObject implements runtime.Object.
+func (j *Job) DeepCopyObject() runtime.Object {
+	out := *j
+	return &out
+}
+
+// Labels synthesizes the label set used for source-aware filtering and
+// selector-based listing, e.g. `source=gcs-prod,type=presubmit,org=openshift`.
+func (j *Job) Labels() labels.Set {
+	return labels.Set{
+		"source": j.Source,
+		"type":   j.Type,
+		"org":    j.Org,
+		"state":  j.State,
+	}
+}
+
+// JobList is the runtime.Object returned from Source.List.
+type JobList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Job `json:"items"`
+}
+
+// DeepCopyObject implements runtime.Object.
+func (l *JobList) DeepCopyObject() runtime.Object {
+	out := *l
+	out.Items = make([]Job, len(l.Items))
+	copy(out.Items, l.Items)
+	return &out
+}
@@ -0,0 +1,66 @@
+// Package jobsource defines a pluggable interface for origins of prow-style
+// job records (Prow Deck, a raw GCS bucket listing, the local filesystem, a
+// generic HTTP manifest) so the indexer is not hard-coded to a single Deck
+// deployment.
+package jobsource
+
+import (
+	"regexp"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// Source is a pluggable origin of job records. It follows the same
+// cache.ListWatch contract prow.ListWatcher already implements, plus Name,
+// so an existing list/watch pair becomes a Source through a thin adapter
+// (prow.NewDeckSource is one).
+type Source interface {
+	// Name uniquely identifies the source. It is used to qualify cache keys
+	// and as the synthesized "source" label so multiple sources can share a
+	// single indexer without their jobs colliding.
+	Name() string
+	List(options metav1.ListOptions) (runtime.Object, error)
+	Watch(options metav1.ListOptions) (watch.Interface, error)
+}
+
+// Config is the on-disk representation of a single source, following the
+// style of scrape-target config: a name, a type, a location, a poll
+// interval, an optional credential reference, relabel-like filter rules,
+// and an optional scraper rules directory.
+type Config struct {
+	Name        string       `json:"name" yaml:"name"`
+	Type        string       `json:"type" yaml:"type"`
+	URL         string       `json:"url" yaml:"url"`
+	Interval    Duration     `json:"interval" yaml:"interval"`
+	Credentials string       `json:"credentialsRef,omitempty" yaml:"credentialsRef,omitempty"`
+	Filters     []FilterRule `json:"filters,omitempty" yaml:"filters,omitempty"`
+	// Rules, if set, points at a scraper rules directory (see
+	// pkg/scraper.LoadRules). Only filesystem sources use it: they scrape
+	// each build's build-log.txt and junit.failures, writing a ".findings"
+	// sidecar next to each one that yields findings. Other source types
+	// ignore it.
+	Rules string `json:"rules,omitempty" yaml:"rules,omitempty"`
+}
+
+// FilterRule keeps or drops a job based on a synthesized label, mirroring
+// Prometheus relabel_configs: if the value of Label matches Regex the job is
+// kept, unless Drop is set in which case it is excluded instead.
+type FilterRule struct {
+	Label string `json:"label" yaml:"label"`
+	Regex string `json:"regex" yaml:"regex"`
+	Drop  bool   `json:"drop,omitempty" yaml:"drop,omitempty"`
+
+	// re is Regex compiled once by LoadConfig, so Keep doesn't recompile it
+	// on every job on every poll.
+	re *regexp.Regexp
+}
+
+// known source types, selected by Config.Type.
+const (
+	TypeDeck       = "deck"
+	TypeGCS        = "gcs"
+	TypeFilesystem = "filesystem"
+	TypeManifest   = "manifest"
+)
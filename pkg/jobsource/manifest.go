@@ -0,0 +1,101 @@
+package jobsource
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// ManifestSource polls a generic HTTP endpoint that returns a JSON array of
+// jobs, for backends that are neither Deck nor a GCS-shaped bucket.
+type ManifestSource struct {
+	name     string
+	url      string
+	interval time.Duration
+	filters  []FilterRule
+	client   *http.Client
+}
+
+// NewManifestSource builds a Source that polls cfg.URL and decodes the
+// response as a JSON array of manifestEntry.
+func NewManifestSource(cfg Config) (*ManifestSource, error) {
+	if len(cfg.URL) == 0 {
+		return nil, fmt.Errorf("source %q is missing a url", cfg.Name)
+	}
+	interval := cfg.Interval.Duration()
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &ManifestSource{name: cfg.Name, url: cfg.URL, interval: interval, filters: cfg.Filters, client: http.DefaultClient}, nil
+}
+
+func (s *ManifestSource) Name() string { return s.name }
+
+func (s *ManifestSource) List(options metav1.ListOptions) (runtime.Object, error) {
+	items, err := s.list()
+	if err != nil {
+		return nil, err
+	}
+	return &JobList{Items: items}, nil
+}
+
+func (s *ManifestSource) Watch(options metav1.ListOptions) (watch.Interface, error) {
+	return NewPollWatcher(s.interval, s.list), nil
+}
+
+// manifestEntry is the JSON shape a generic manifest endpoint is expected to
+// return one of, per job.
+type manifestEntry struct {
+	Name           string    `json:"name"`
+	Type           string    `json:"type"`
+	Org            string    `json:"org"`
+	State          string    `json:"state"`
+	URL            string    `json:"url"`
+	BuildID        string    `json:"buildID"`
+	CompletionTime time.Time `json:"completionTime"`
+}
+
+func (s *ManifestSource) list() ([]Job, error) {
+	resp, err := s.client.Get(s.url)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch manifest %s: %v", s.url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unable to fetch manifest %s: %s", s.url, resp.Status)
+	}
+
+	var entries []manifestEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("unable to decode manifest %s: %v", s.url, err)
+	}
+
+	var result []Job
+	for _, e := range entries {
+		// prefix with the source name so two sources that happen to
+		// produce the same job name never collide on the same cache key.
+		job := Job{
+			ObjectMeta:     metav1.ObjectMeta{Name: s.name + "/" + e.Name},
+			Source:         s.name,
+			Type:           e.Type,
+			Org:            e.Org,
+			State:          e.State,
+			URL:            e.URL,
+			BuildID:        e.BuildID,
+			CompletionTime: e.CompletionTime,
+		}
+		keep, err := Keep(s.filters, job.Labels())
+		if err != nil {
+			return nil, err
+		}
+		if keep {
+			result = append(result, job)
+		}
+	}
+	return result, nil
+}
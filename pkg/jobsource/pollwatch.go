@@ -0,0 +1,110 @@
+package jobsource
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// PollWatcher is a watch.Interface backed by periodic polling of a lister
+// function. It diffs each poll against the last observed set (keyed by job
+// name) and emits Added/Modified/Deleted events, so every Source behaves
+// like a real watch to callers even though its backend has no native
+// push/watch primitive. It is shared by every Source implementation in this
+// package (GCS, filesystem, manifest); the Deck source in package prow uses
+// that package's own diffWatcher instead.
+type PollWatcher struct {
+	ch   chan watch.Event
+	done chan struct{}
+
+	lock   sync.Mutex
+	closed bool
+}
+
+// NewPollWatcher starts polling list every interval until Stop is called.
+func NewPollWatcher(interval time.Duration, list func() ([]Job, error)) *PollWatcher {
+	w := &PollWatcher{
+		ch:   make(chan watch.Event, 100),
+		done: make(chan struct{}),
+	}
+	go w.run(interval, list)
+	return w
+}
+
+func (w *PollWatcher) run(interval time.Duration, list func() ([]Job, error)) {
+	defer close(w.ch)
+	seen := map[string]Job{}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	if !w.poll(&seen, list) {
+		return
+	}
+	for {
+		select {
+		case <-ticker.C:
+			if !w.poll(&seen, list) {
+				return
+			}
+		case <-w.done:
+			return
+		}
+	}
+}
+
+func (w *PollWatcher) poll(seen *map[string]Job, list func() ([]Job, error)) bool {
+	items, err := list()
+	if err != nil {
+		return w.emit(watch.Event{Type: watch.Error})
+	}
+
+	next := make(map[string]Job, len(items))
+	for _, item := range items {
+		item := item
+		next[item.Name] = item
+		eventType := watch.Added
+		if prev, ok := (*seen)[item.Name]; ok {
+			if prev.State == item.State && prev.CompletionTime.Equal(item.CompletionTime) {
+				continue
+			}
+			eventType = watch.Modified
+		}
+		if !w.emit(watch.Event{Type: eventType, Object: item.DeepCopyObject()}) {
+			return false
+		}
+	}
+	for name, item := range *seen {
+		if _, ok := next[name]; !ok {
+			if !w.emit(watch.Event{Type: watch.Deleted, Object: item.DeepCopyObject()}) {
+				return false
+			}
+		}
+	}
+	*seen = next
+	return true
+}
+
+func (w *PollWatcher) emit(event watch.Event) bool {
+	select {
+	case w.ch <- event:
+		return true
+	case <-w.done:
+		return false
+	}
+}
+
+// Stop implements watch.Interface.
+func (w *PollWatcher) Stop() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if !w.closed {
+		close(w.done)
+		w.closed = true
+	}
+}
+
+// ResultChan implements watch.Interface.
+func (w *PollWatcher) ResultChan() <-chan watch.Event {
+	return w.ch
+}
@@ -0,0 +1,92 @@
+package nettransport
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// deadlineConn layers SetDeadline/SetReadDeadline/SetWriteDeadline support
+// on top of an underlying net.Conn using a timer paired with a cancel
+// channel. The cancel channel is recreated on every Set*Deadline call,
+// guarded by a mutex, so resetting a connection's deadline repeatedly (as
+// net/http does on every request) never races the previous deadline's timer
+// firing concurrently with a new one being set.
+type deadlineConn struct {
+	net.Conn
+
+	lock        sync.Mutex
+	readCancel  chan struct{}
+	writeCancel chan struct{}
+	readTimer   *time.Timer
+	writeTimer  *time.Timer
+}
+
+func newDeadlineConn(conn net.Conn) *deadlineConn {
+	return &deadlineConn{Conn: conn}
+}
+
+// SetDeadline implements net.Conn.
+func (c *deadlineConn) SetDeadline(t time.Time) error {
+	if err := c.SetReadDeadline(t); err != nil {
+		return err
+	}
+	return c.SetWriteDeadline(t)
+}
+
+// SetReadDeadline implements net.Conn.
+func (c *deadlineConn) SetReadDeadline(t time.Time) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.readTimer != nil {
+		c.readTimer.Stop()
+	}
+	cancel := make(chan struct{})
+	c.readCancel = cancel
+	// clear any deadline an earlier timer already applied, so a new
+	// deadline re-enables reads the way it would on a real socket.
+	if err := c.Conn.SetReadDeadline(time.Time{}); err != nil || t.IsZero() {
+		return err
+	}
+	c.readTimer = time.AfterFunc(time.Until(t), func() {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		// re-check under the lock that this is still the active cancel
+		// pair: without this, a concurrent SetReadDeadline could install a
+		// later deadline between the timer firing and this callback
+		// acquiring the lock, and we'd stomp it back down to "now".
+		if c.readCancel != cancel {
+			return
+		}
+		c.Conn.SetReadDeadline(time.Now())
+	})
+	return nil
+}
+
+// SetWriteDeadline implements net.Conn.
+func (c *deadlineConn) SetWriteDeadline(t time.Time) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.writeTimer != nil {
+		c.writeTimer.Stop()
+	}
+	cancel := make(chan struct{})
+	c.writeCancel = cancel
+	if err := c.Conn.SetWriteDeadline(time.Time{}); err != nil || t.IsZero() {
+		return err
+	}
+	c.writeTimer = time.AfterFunc(time.Until(t), func() {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		// see the matching comment in SetReadDeadline: re-check under the
+		// lock that this is still the active cancel pair before stomping
+		// the underlying deadline.
+		if c.writeCancel != cancel {
+			return
+		}
+		c.Conn.SetWriteDeadline(time.Now())
+	})
+	return nil
+}
@@ -0,0 +1,83 @@
+package nettransport
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+)
+
+// errClosed is returned from Accept and Dial once the Listener has been
+// closed.
+var errClosed = errors.New("nettransport: listener closed")
+
+// LoopbackListener is a net.Listener backed entirely by in-process
+// connection pairs rather than a host-kernel socket. Pair it with Dial to
+// connect a client to it; every accepted connection honors
+// SetDeadline/SetReadDeadline/SetWriteDeadline via deadlineConn, the same as
+// a real socket would.
+//
+// This is named LoopbackListener rather than just Listener because it only
+// satisfies the in-process testing use case: it is not a substitute for a
+// ListenFunc/DialContextFunc backed by an actual packet-level stack (see
+// the package doc). Do not reach for this when the ask is to expose
+// ci-search on a tap device or behind an external SOCKS-like sidecar -
+// neither is implemented here.
+type LoopbackListener struct {
+	addr    net.Addr
+	connCh  chan net.Conn
+	closeCh chan struct{}
+	once    sync.Once
+}
+
+// NewLoopbackListener returns a LoopbackListener reporting addr from
+// Addr(). addr is metadata only - no socket is opened, so it need not be
+// bindable.
+func NewLoopbackListener(addr net.Addr) *LoopbackListener {
+	return &LoopbackListener{
+		addr:    addr,
+		connCh:  make(chan net.Conn),
+		closeCh: make(chan struct{}),
+	}
+}
+
+// Accept implements net.Listener.
+func (l *LoopbackListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.connCh:
+		return c, nil
+	case <-l.closeCh:
+		return nil, errClosed
+	}
+}
+
+// Close implements net.Listener.
+func (l *LoopbackListener) Close() error {
+	l.once.Do(func() { close(l.closeCh) })
+	return nil
+}
+
+// Addr implements net.Listener.
+func (l *LoopbackListener) Addr() net.Addr {
+	return l.addr
+}
+
+// Dial connects a new client net.Conn to this LoopbackListener, handing the
+// server side to whatever goroutine is blocked in Accept. Its signature
+// matches DialContextFunc so it can be used directly as (or wrapped by) the
+// dialer a prow Client routes connections through.
+func (l *LoopbackListener) Dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	server, client := net.Pipe()
+	select {
+	case l.connCh <- newDeadlineConn(server):
+	case <-l.closeCh:
+		server.Close()
+		client.Close()
+		return nil, errClosed
+	case <-ctx.Done():
+		server.Close()
+		client.Close()
+		return nil, ctx.Err()
+	}
+	return newDeadlineConn(client), nil
+}
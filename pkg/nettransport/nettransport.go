@@ -0,0 +1,44 @@
+// Package nettransport defines the DialContextFunc/ListenFunc extension
+// points that let ci-search's HTTP server and its prow client route
+// connections through something other than the host kernel's socket API,
+// plus LoopbackListener, an in-process-only net.Listener implementation.
+//
+// Scope note: exposing ci-search on a tap device or behind an external
+// SOCKS-like sidecar needs a ListenFunc/DialContextFunc backed by an actual
+// packet-level stack (e.g. gvisor.dev/gvisor/pkg/tcpip), which is not
+// implemented in this package and is not a small addition on top of it -
+// it's a separate backend that happens to plug into the same two
+// interfaces. Treat that as its own request rather than follow-up work on
+// LoopbackListener; this package only provides the seam (the two
+// interfaces above) and a loopback implementation good enough for
+// in-process testing today.
+package nettransport
+
+import (
+	"context"
+	"net"
+	"net/http"
+)
+
+// DialContextFunc matches http.Transport.DialContext, letting the prow
+// Client dial outbound connections through the same transport a Listener
+// accepts them on.
+type DialContextFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// ListenFunc matches net.Listen's signature, letting handleIndex and
+// handleConfig bind to a userspace listener instead of a host-kernel
+// socket.
+type ListenFunc func(network, addr string) (net.Listener, error)
+
+// RoundTripper builds an *http.Transport that dials every outbound
+// connection through dial instead of the host network stack. Assign the
+// result to an http.Client's Transport field (e.g. the one backing the prow
+// Client) to route it through the same DialContextFunc a Listener was built
+// with.
+func RoundTripper(dial DialContextFunc) http.RoundTripper {
+	return &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return dial(ctx, network, addr)
+		},
+	}
+}
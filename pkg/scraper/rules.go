@@ -0,0 +1,136 @@
+// Package scraper applies user-defined extraction rules against indexed
+// artifacts (build-log.txt, junit.failures) as they are fetched, producing
+// structured "findings" that can be searched alongside the raw text.
+package scraper
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Rule describes a single extraction rule loaded from a JSON or YAML file.
+type Rule struct {
+	// Name identifies the rule and is used as the finding's rule name when
+	// aggregating counts.
+	Name string `json:"name" yaml:"name"`
+	// Target is a glob matched against the base name of the artifact the
+	// rule applies to, e.g. "build-log.txt" or "junit.failures".
+	Target string `json:"target" yaml:"target"`
+	// Pattern is a regular expression with named capture groups that is
+	// evaluated against each line of the target artifact.
+	Pattern string `json:"pattern" yaml:"pattern"`
+	// PostFilter is an optional text/template boolean expression evaluated
+	// against the named capture groups; a match is discarded unless the
+	// rendered output, with surrounding whitespace trimmed, is "true".
+	PostFilter string `json:"postFilter,omitempty" yaml:"postFilter,omitempty"`
+	// Template builds the canonical finding string from the named capture
+	// groups, e.g. "panic:{{.goroutine}}" or "e2e-test:{{.name}}:{{.reason}}".
+	Template string `json:"template" yaml:"template"`
+
+	re         *regexp.Regexp
+	postFilter *template.Template
+	tmpl       *template.Template
+}
+
+// compile validates the rule and prepares it for repeated use against many
+// artifacts.
+func (r *Rule) compile() error {
+	if len(r.Name) == 0 {
+		return fmt.Errorf("rule is missing a name")
+	}
+	if len(r.Target) == 0 {
+		return fmt.Errorf("rule %q is missing a target glob", r.Name)
+	}
+	if len(r.Pattern) == 0 {
+		return fmt.Errorf("rule %q is missing a pattern", r.Name)
+	}
+	re, err := regexp.Compile(r.Pattern)
+	if err != nil {
+		return fmt.Errorf("rule %q has an invalid pattern: %v", r.Name, err)
+	}
+	named := false
+	for _, name := range re.SubexpNames() {
+		if len(name) > 0 {
+			named = true
+			break
+		}
+	}
+	if !named {
+		return fmt.Errorf("rule %q pattern has no named capture groups", r.Name)
+	}
+	r.re = re
+
+	if len(r.Template) == 0 {
+		return fmt.Errorf("rule %q is missing an output template", r.Name)
+	}
+	tmpl, err := template.New(r.Name).Option("missingkey=zero").Parse(r.Template)
+	if err != nil {
+		return fmt.Errorf("rule %q has an invalid template: %v", r.Name, err)
+	}
+	r.tmpl = tmpl
+
+	if len(r.PostFilter) > 0 {
+		postFilter, err := template.New(r.Name + "-postfilter").Option("missingkey=zero").Parse(r.PostFilter)
+		if err != nil {
+			return fmt.Errorf("rule %q has an invalid postFilter: %v", r.Name, err)
+		}
+		r.postFilter = postFilter
+	}
+	return nil
+}
+
+// Matches returns true if the rule applies to the given artifact file name.
+func (r *Rule) Matches(name string) bool {
+	ok, err := path.Match(r.Target, filepath.Base(name))
+	return err == nil && ok
+}
+
+// LoadRules reads every *.json, *.yaml, and *.yml file in dir and returns the
+// compiled rules. Files that fail to parse or compile are reported as a
+// single aggregate error so a typo in one rule does not silently disable the
+// rest.
+func LoadRules(dir string) ([]*Rule, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read scraper rules directory %s: %v", dir, err)
+	}
+
+	var rules []*Rule
+	var errs []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if ext != ".json" && ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+		var rule Rule
+		if err := yaml.Unmarshal(data, &rule); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+		if err := rule.compile(); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+		rules = append(rules, &rule)
+	}
+	if len(errs) > 0 {
+		return rules, fmt.Errorf("failed to load %d rule(s):\n%s", len(errs), strings.Join(errs, "\n"))
+	}
+	return rules, nil
+}
@@ -0,0 +1,143 @@
+package scraper
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Finding is a single structured extraction produced by a Rule matching a
+// line within an artifact.
+type Finding struct {
+	// Rule is the name of the rule that produced this finding.
+	Rule string `json:"rule"`
+	// Line is the 1-indexed line number within the artifact the match was
+	// found on.
+	Line int `json:"line"`
+	// Text is the canonical finding string built from the rule's template,
+	// e.g. "panic:myGoroutine" or "e2e-test:MyTest:timeout".
+	Text string `json:"text"`
+}
+
+// Scraper applies a fixed set of rules against artifacts as they are
+// fetched.
+type Scraper struct {
+	rules []*Rule
+}
+
+// NewScraper loads every rule in rulesDir and returns a Scraper that applies
+// all of them.
+func NewScraper(rulesDir string) (*Scraper, error) {
+	rules, err := LoadRules(rulesDir)
+	if err != nil {
+		return nil, err
+	}
+	return &Scraper{rules: rules}, nil
+}
+
+// Rules returns the set of rules this scraper will apply, for callers that
+// want to report rule names.
+func (s *Scraper) Rules() []*Rule {
+	return s.rules
+}
+
+// Scan reads r line by line and applies every rule whose Target glob matches
+// name, returning the findings in the order they were encountered.
+func (s *Scraper) Scan(name string, r io.Reader) ([]Finding, error) {
+	var applicable []*Rule
+	for _, rule := range s.rules {
+		if rule.Matches(name) {
+			applicable = append(applicable, rule)
+		}
+	}
+	if len(applicable) == 0 {
+		return nil, nil
+	}
+
+	var findings []Finding
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Bytes()
+		for _, rule := range applicable {
+			finding, ok, err := rule.apply(line)
+			if err != nil {
+				return findings, fmt.Errorf("rule %q failed on %s:%d: %v", rule.Name, name, lineNum, err)
+			}
+			if !ok {
+				continue
+			}
+			findings = append(findings, Finding{Rule: rule.Name, Line: lineNum, Text: finding})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return findings, err
+	}
+	return findings, nil
+}
+
+// apply evaluates the rule against a single line, returning the rendered
+// finding string and whether the rule matched and passed its post-filter.
+func (r *Rule) apply(line []byte) (string, bool, error) {
+	match := r.re.FindSubmatch(line)
+	if match == nil {
+		return "", false, nil
+	}
+	groups := make(map[string]string, len(match))
+	for i, name := range r.re.SubexpNames() {
+		if i == 0 || len(name) == 0 {
+			continue
+		}
+		groups[name] = string(match[i])
+	}
+
+	if r.postFilter != nil {
+		var buf bytes.Buffer
+		if err := r.postFilter.Execute(&buf, groups); err != nil {
+			return "", false, err
+		}
+		if strings.TrimSpace(buf.String()) != "true" {
+			return "", false, nil
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := r.tmpl.Execute(&buf, groups); err != nil {
+		return "", false, err
+	}
+	return buf.String(), true, nil
+}
+
+// SidecarPath returns the path the findings for an artifact are written to,
+// alongside the artifact itself.
+func SidecarPath(artifactPath string) string {
+	return artifactPath + ".findings"
+}
+
+// WriteSidecar writes findings as newline-delimited JSON next to the
+// artifact they were extracted from, so they can be indexed using the same
+// pipeline as the raw text.
+func WriteSidecar(artifactPath string, findings []Finding) error {
+	if len(findings) == 0 {
+		return nil
+	}
+	f, err := os.Create(SidecarPath(artifactPath))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	for _, finding := range findings {
+		if err := enc.Encode(finding); err != nil {
+			return err
+		}
+	}
+	return nil
+}
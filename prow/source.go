@@ -0,0 +1,119 @@
+package prow
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/Global19-atlassian-net/ci-search/pkg/jobsource"
+)
+
+// NewDeckSource adapts the existing Deck-backed ListWatcher into a
+// jobsource.Source so it can be combined with other backends (GCS,
+// filesystem, a generic HTTP manifest) behind jobsource.NewInformer. name
+// qualifies the jobs this source produces, e.g. "deck" or "deck-downstream"
+// when more than one Deck deployment is configured.
+func NewDeckSource(client *Client, name string, interval time.Duration) jobsource.Source {
+	return &deckSource{name: name, lw: &ListWatcher{client: client, interval: interval}}
+}
+
+type deckSource struct {
+	name string
+	lw   *ListWatcher
+}
+
+func (s *deckSource) Name() string { return s.name }
+
+func (s *deckSource) List(options metav1.ListOptions) (runtime.Object, error) {
+	obj, err := s.lw.List(options)
+	if err != nil {
+		return nil, err
+	}
+	list, ok := obj.(*JobList)
+	if !ok {
+		return nil, fmt.Errorf("deck source %q: unexpected list type %T", s.name, obj)
+	}
+	items := make([]jobsource.Job, 0, len(list.Items))
+	for i := range list.Items {
+		items = append(items, s.toJobSourceJob(&list.Items[i]))
+	}
+	return &jobsource.JobList{Items: items}, nil
+}
+
+func (s *deckSource) Watch(options metav1.ListOptions) (watch.Interface, error) {
+	w, err := s.lw.Watch(options)
+	if err != nil {
+		return nil, err
+	}
+	return &deckSourceWatch{name: s.name, source: s, w: w}, nil
+}
+
+// toJobSourceJob translates the Deck/prow representation of a job into the
+// common jobsource.Job shape shared by every backend. The name is prefixed
+// with the source name so two sources that happen to produce the same job
+// (e.g. this Deck and a GCS mirror of the same upstream) never collide on
+// the same cache key.
+func (s *deckSource) toJobSourceJob(job *Job) jobsource.Job {
+	return jobsource.Job{
+		ObjectMeta:     metav1.ObjectMeta{Name: s.name + "/" + job.ObjectMeta.Name},
+		Source:         s.name,
+		Type:           string(job.Spec.Type),
+		Org:            refsOrg(job.Spec),
+		State:          string(job.Status.State),
+		URL:            job.Status.URL,
+		BuildID:        job.Status.BuildID,
+		CompletionTime: completionTime(job.Status),
+	}
+}
+
+// refsOrg returns the org the job was triggered for, matching the
+// `org=...` label used for filter rules and selectors.
+func refsOrg(spec JobSpec) string {
+	if spec.Refs != nil {
+		return spec.Refs.Org
+	}
+	return ""
+}
+
+// completionTime normalizes the Status.CompletionTime pointer the
+// underlying ProwJob type uses into a plain time.Time, leaving the zero
+// value when the job hasn't completed.
+func completionTime(status JobStatus) time.Time {
+	if status.CompletionTime == nil {
+		return time.Time{}
+	}
+	return status.CompletionTime.Time
+}
+
+// deckSourceWatch wraps the Deck ListWatcher's watch.Interface so emitted
+// events carry jobsource.Job objects instead of the raw Job type.
+type deckSourceWatch struct {
+	name   string
+	source *deckSource
+	w      watch.Interface
+
+	ch chan watch.Event
+}
+
+func (w *deckSourceWatch) Stop() { w.w.Stop() }
+
+func (w *deckSourceWatch) ResultChan() <-chan watch.Event {
+	if w.ch != nil {
+		return w.ch
+	}
+	w.ch = make(chan watch.Event, 100)
+	go func() {
+		defer close(w.ch)
+		for event := range w.w.ResultChan() {
+			if job, ok := event.Object.(*Job); ok {
+				translated := w.source.toJobSourceJob(job)
+				event.Object = &translated
+			}
+			w.ch <- event
+		}
+	}()
+	return w.ch
+}